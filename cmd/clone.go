@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/CapSens/cappuccino/engine"
+	"github.com/CapSens/cappuccino/vcs"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+/*
+gitOptions
+Holds the flags shared by every subcommand that needs to reach a
+remote repository (run, describe).
+*/
+type gitOptions struct {
+	GitUrl          string
+	Branch          string
+	Ref             string
+	Depth           int
+	SSHKey          string
+	Token           string
+	InsecureSkipTLS bool
+}
+
+/*
+addRefFlags
+Registers the flags controlling what to check out and how to
+authenticate, shared by run and describe.
+*/
+func addRefFlags(cmd *cobra.Command, opts *gitOptions) {
+	cmd.Flags().StringVarP(&opts.Branch, "branch", "b", "master", "Branch to work with")
+	cmd.Flags().StringVar(&opts.Ref, "ref", "", "Branch, tag or commit SHA to check out (overrides --branch)")
+	cmd.Flags().IntVar(&opts.Depth, "depth", 0, "Create a shallow clone with a history truncated to this many commits")
+	cmd.Flags().StringVar(&opts.SSHKey, "ssh-key", "", "Path to an SSH private key to authenticate with (the SSH agent is used instead for git@ URLs when neither --ssh-key nor --token is given)")
+	cmd.Flags().StringVar(&opts.Token, "token", "", "Token used as HTTP basic auth to authenticate with the git host")
+	cmd.Flags().BoolVar(&opts.InsecureSkipTLS, "insecure-skip-tls", false, "Skip TLS certificate verification when cloning over HTTPS")
+}
+
+/*
+startEngine
+Displays a welcome message and current version once libraries are ready.
+*/
+func startEngine() {
+	engine.Text(fmt.Sprintf("Starting engine (%s)", VERSION), color.FgYellow)
+}
+
+/*
+verifyGitUrl
+Checks that the git url given in parameter is in a valid format.
+Same logic should be applied for a SVN cloning process.
+*/
+func verifyGitUrl(href string) error {
+	engine.Text(fmt.Sprintf("Checking git url format (%s)", href), color.FgYellow)
+	regex := "((git|ssh|http(s)?)|(git@[\\w\\.]+))(:(//)?)([\\w\\.@\\:/\\-~]+)(\\.git)(/)?"
+
+	match, _ := regexp.MatchString(regex, href)
+	if !match {
+		return fmt.Errorf("git url format is not valid")
+	}
+
+	engine.Text("Git url format successfuly verified", color.FgYellow)
+	return nil
+}
+
+/*
+cloneRepo
+Clones the repository described by opts into targetDir (or a
+directory named after the repository, under the current directory,
+when targetDir is empty), resolving ref as a branch, tag or commit
+SHA (falling back to --branch when --ref is empty) and
+authenticating with an SSH key, the SSH agent or a token when
+provided.
+*/
+func cloneRepo(opts gitOptions, targetDir string) (*vcs.Repo, error) {
+	ref := opts.Ref
+	if ref == "" {
+		ref = opts.Branch
+	}
+
+	engine.Text(fmt.Sprintf("Cloning git repository (ref: %s)", ref), color.FgYellow)
+
+	repo, err := vcs.Clone(context.Background(), vcs.VCSOptions{
+		URL:             opts.GitUrl,
+		Ref:             ref,
+		Depth:           opts.Depth,
+		TargetDir:       targetDir,
+		InsecureSkipTLS: opts.InsecureSkipTLS,
+		Auth: vcs.Auth{
+			SSHKeyPath:  opts.SSHKey,
+			UseSSHAgent: opts.SSHKey == "" && opts.Token == "" && strings.HasPrefix(opts.GitUrl, "git@"),
+			Token:       opts.Token,
+		},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("cloning %s: %w", opts.GitUrl, err)
+	}
+
+	engine.Text(fmt.Sprintf("Cloned into %s", repo.Path()), color.FgYellow)
+
+	return repo, nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import "testing"
+
+func TestParseSetValues(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"empty", nil, map[string]string{}, false},
+		{"single", []string{"name=value"}, map[string]string{"name": "value"}, false},
+		{"multiple", []string{"a=1", "b=2"}, map[string]string{"a": "1", "b": "2"}, false},
+		{"value with equals", []string{"url=https://a.b/c?d=e"}, map[string]string{"url": "https://a.b/c?d=e"}, false},
+		{"missing equals", []string{"invalid"}, nil, true},
+	}
+
+	for _, c := range cases {
+		got, err := parseSetValues(c.raw)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseSetValues(%v) expected an error, got none", c.raw)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("parseSetValues(%v) returned unexpected error: %v", c.raw, err)
+		}
+
+		if len(got) != len(c.want) {
+			t.Fatalf("parseSetValues(%v) = %v, want %v", c.raw, got, c.want)
+		}
+		for k, v := range c.want {
+			if got[k] != v {
+				t.Errorf("parseSetValues(%v) = %v, want %v", c.raw, got, c.want)
+			}
+		}
+	}
+}
+
+func TestVerifyGitUrl(t *testing.T) {
+	cases := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://github.com/CapSens/cappuccino.git", false},
+		{"git@github.com:CapSens/cappuccino.git", false},
+		{"ssh://git@github.com/CapSens/cappuccino.git", false},
+		{"not a url", true},
+		{"", true},
+	}
+
+	for _, c := range cases {
+		err := verifyGitUrl(c.url)
+		if c.wantErr && err == nil {
+			t.Errorf("verifyGitUrl(%q) expected an error, got none", c.url)
+		}
+		if !c.wantErr && err != nil {
+			t.Errorf("verifyGitUrl(%q) returned unexpected error: %v", c.url, err)
+		}
+	}
+}
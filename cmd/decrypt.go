@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/CapSens/cappuccino/crypto"
+	"github.com/CapSens/cappuccino/engine"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var decryptKeyFile string
+
+/*
+decryptCmd
+Rewrites every "enc:<base64-ciphertext>" field of a .cappuccino.yml
+in place back into a `!secret` field, so a template author can
+edit it before re-running `cappuccino encrypt`.
+*/
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt <file>",
+	Short: "Decrypt every enc: field in a .cappuccino.yml in place",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := crypto.ResolveKey(decryptKeyFile)
+		if err != nil {
+			return err
+		}
+
+		if err := crypto.DecryptFile(args[0], key); err != nil {
+			return err
+		}
+
+		engine.Text(fmt.Sprintf("Decrypted secrets in %s", args[0]), color.FgGreen)
+		return nil
+	},
+}
+
+func init() {
+	decryptCmd.Flags().StringVar(&decryptKeyFile, "key-file", "", "Path to an age identity file (defaults to $CAPPUCCINO_KEY)")
+	rootCmd.AddCommand(decryptCmd)
+}
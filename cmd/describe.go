@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/CapSens/cappuccino/engine"
+	"github.com/spf13/cobra"
+)
+
+var describeOpts gitOptions
+
+/*
+describeCmd
+Clones a template and prints its action list without executing it,
+useful to review what a template does before running it for real.
+*/
+var describeCmd = &cobra.Command{
+	Use:   "describe <url>",
+	Short: "Fetch a template and print its action list without executing it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		describeOpts.GitUrl = args[0]
+
+		startEngine()
+
+		if err := verifyGitUrl(describeOpts.GitUrl); err != nil {
+			return err
+		}
+
+		tmpDir, err := ioutil.TempDir("", "cappuccino-describe-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		targetDir := filepath.Join(tmpDir, "repo")
+
+		repo, err := cloneRepo(describeOpts, targetDir)
+		if err != nil {
+			return err
+		}
+
+		root := repo.Path()
+
+		config, err := engine.LoadConfig(filepath.Join(root, ".cappuccino.yml"))
+		if err != nil {
+			return err
+		}
+
+		engine.DisplayVersion(config)
+
+		return engine.Run(config, root, engine.ModeDryRun, nil, nil)
+	},
+}
+
+func init() {
+	addRefFlags(describeCmd, &describeOpts)
+	rootCmd.AddCommand(describeCmd)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/CapSens/cappuccino/crypto"
+	"github.com/CapSens/cappuccino/engine"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var encryptKeyFile string
+
+/*
+encryptCmd
+Rewrites every `!secret` field of a .cappuccino.yml in place into
+its "enc:<base64-ciphertext>" form, so the file can be committed
+alongside the rest of a template.
+*/
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt <file>",
+	Short: "Encrypt every !secret field in a .cappuccino.yml in place",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := crypto.ResolveKey(encryptKeyFile)
+		if err != nil {
+			return err
+		}
+
+		if err := crypto.EncryptFile(args[0], key); err != nil {
+			return err
+		}
+
+		engine.Text(fmt.Sprintf("Encrypted secrets in %s", args[0]), color.FgGreen)
+		return nil
+	},
+}
+
+func init() {
+	encryptCmd.Flags().StringVar(&encryptKeyFile, "key-file", "", "Path to an age identity file (defaults to $CAPPUCCINO_KEY)")
+	rootCmd.AddCommand(encryptCmd)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/CapSens/cappuccino/engine"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const starterConfig = `engine: cappuccino
+version: "0.1.2"
+actions:
+  - name: Say hello
+    type: exec
+    content:
+      - command: echo "Hello from your new cappuccino template!"
+`
+
+/*
+initCmd
+Scaffolds a starter .cappuccino.yml and .cappuccino/ directory in
+the current working directory, for authors starting a new template
+from scratch.
+*/
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Scaffold a starter .cappuccino.yml and .cappuccino/ directory",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat(".cappuccino.yml"); err == nil {
+			return fmt.Errorf(".cappuccino.yml already exists")
+		}
+
+		if err := os.MkdirAll(".cappuccino", 0755); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(".cappuccino.yml", []byte(starterConfig), 0644); err != nil {
+			return err
+		}
+
+		engine.Text("Scaffolded .cappuccino.yml and .cappuccino/", color.FgGreen)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// VERSION is the current Cappuccino release. Please refer to the
+// CHANGELOG for related changes.
+const VERSION = "0.1.2"
+
+var debug bool
+
+/*
+rootCmd
+The cappuccino entry point. Every subcommand (run, validate, describe,
+init, version, encrypt, decrypt) is attached to it in their own file's init().
+*/
+var rootCmd = &cobra.Command{
+	Use:   "cappuccino",
+	Short: "Cappuccino scaffolds projects from git-hosted templates",
+}
+
+/*
+Execute
+Runs the root command, exiting with a non-zero status if it failed.
+*/
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Run in debug mode")
+}
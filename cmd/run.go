@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/CapSens/cappuccino/engine"
+	"github.com/CapSens/cappuccino/transaction"
+	"github.com/CapSens/cappuccino/variables"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var runOpts gitOptions
+var dryRun bool
+var valuesFile string
+var setValues []string
+var nonInteractive bool
+var noRollback bool
+var keepSnapshot bool
+var sandboxFlag bool
+var keyFile string
+
+/*
+runCmd
+Clones a git repository and executes its .cappuccino.yml, the
+historical (and default) behavior of Cappuccino.
+*/
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Clone a git repository and execute its .cappuccino.yml",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		startEngine()
+
+		if err := verifyGitUrl(runOpts.GitUrl); err != nil {
+			return err
+		}
+
+		repo, err := cloneRepo(runOpts, "")
+		if err != nil {
+			return err
+		}
+		root := repo.Path()
+
+		config, err := engine.LoadConfig(filepath.Join(root, ".cappuccino.yml"))
+		if err != nil {
+			return err
+		}
+
+		engine.DisplayVersion(config)
+		config.Sandbox = config.Sandbox || sandboxFlag
+
+		if err := engine.DecryptConfig(config, keyFile); err != nil {
+			return err
+		}
+
+		set, err := parseSetValues(setValues)
+		if err != nil {
+			return err
+		}
+
+		values, err := variables.Resolve(config, root, variables.ResolveOptions{
+			ValuesFile:     valuesFile,
+			Set:            set,
+			NonInteractive: nonInteractive,
+		})
+		if err != nil {
+			return err
+		}
+
+		mode := engine.ModeExecute
+		if dryRun {
+			mode = engine.ModeDryRun
+		}
+
+		var tx *transaction.Transaction
+		if mode == engine.ModeExecute && !noRollback {
+			tx, err = transaction.Begin(root, keepSnapshot)
+			if err != nil {
+				return err
+			}
+		}
+
+		runErr := engine.Run(config, root, mode, values, tx)
+
+		if tx == nil {
+			return runErr
+		}
+
+		if runErr == nil {
+			return tx.Commit()
+		}
+
+		report, rollbackErr := tx.Rollback()
+		for _, line := range report {
+			engine.Text(fmt.Sprintf("\t-> %s", line), color.FgYellow)
+		}
+
+		if rollbackErr != nil {
+			return fmt.Errorf("%v (rollback also failed: %w)", runErr, rollbackErr)
+		}
+
+		return fmt.Errorf("%v (rolled back)", runErr)
+	},
+}
+
+/*
+parseSetValues
+Turns a list of "key=value" strings, as collected from repeated
+--set flags, into a name -> value map.
+*/
+func parseSetValues(raw []string) (map[string]string, error) {
+	set := map[string]string{}
+
+	for _, entry := range raw {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid --set value %q, expected key=value", entry)
+		}
+		set[parts[0]] = parts[1]
+	}
+
+	return set, nil
+}
+
+func init() {
+	runCmd.Flags().StringVarP(&runOpts.GitUrl, "git", "g", "", "Git repository to clone")
+	runCmd.MarkFlagRequired("git")
+	addRefFlags(runCmd, &runOpts)
+	runCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Log every planned action without touching the filesystem")
+	runCmd.Flags().StringVar(&valuesFile, "values", "", "YAML file of variable name -> value overrides")
+	runCmd.Flags().StringArrayVar(&setValues, "set", nil, "Set a variable value as key=value (repeatable)")
+	runCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Fail instead of prompting when a required variable is missing")
+	runCmd.Flags().BoolVar(&noRollback, "no-rollback", false, "Don't snapshot the working directory or roll back on failure")
+	runCmd.Flags().BoolVar(&keepSnapshot, "keep-snapshot", false, "Keep the pre-run snapshot on disk instead of deleting it, for debugging")
+	runCmd.Flags().BoolVar(&sandboxFlag, "sandbox", false, "Run exec actions inside a disposable Docker container instead of on the host")
+	runCmd.Flags().StringVar(&keyFile, "key-file", "", "Path to an age identity file used to decrypt enc: values (defaults to $CAPPUCCINO_KEY)")
+
+	rootCmd.AddCommand(runCmd)
+}
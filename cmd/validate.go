@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/CapSens/cappuccino/engine"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+/*
+validateCmd
+Parses a .cappuccino.yml (given directly or as the directory that
+contains it) and checks every action/content struct, including
+that every referenced source path exists, without executing
+anything.
+*/
+var validateCmd = &cobra.Command{
+	Use:   "validate <path>",
+	Short: "Validate a .cappuccino.yml without executing it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		configPath := path
+		dir := filepath.Dir(path)
+		if info.IsDir() {
+			configPath = filepath.Join(path, ".cappuccino.yml")
+			dir = path
+		}
+
+		config, err := engine.LoadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		if err := engine.Run(config, dir, engine.ModeValidate, nil, nil); err != nil {
+			return err
+		}
+
+		engine.Text("Configuration is valid", color.FgGreen)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
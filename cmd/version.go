@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+/*
+versionCmd
+Displays the current Cappuccino version.
+*/
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Display program version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Printf("cappuccino %s\n", VERSION)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
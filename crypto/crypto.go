@@ -0,0 +1,125 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// encPrefix marks a config value as ciphertext rather than plaintext.
+const encPrefix = "enc:"
+
+/*
+Key
+Wraps the age identities (and their matching recipients, for
+encrypting) used to read and write "enc:" values. Resolved once
+per command invocation via ResolveKey.
+*/
+type Key struct {
+	identities []age.Identity
+	recipients []age.Recipient
+}
+
+/*
+ResolveKey
+Locates an age identity file from, in order, keyFile,
+$CAPPUCCINO_KEY, and parses it into a Key usable for both
+encryption and decryption.
+*/
+func ResolveKey(keyFile string) (*Key, error) {
+	path := keyFile
+	if path == "" {
+		path = os.Getenv("CAPPUCCINO_KEY")
+	}
+	if path == "" {
+		return nil, fmt.Errorf("no encryption key: pass --key-file or set $CAPPUCCINO_KEY")
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file %s: %w", path, err)
+	}
+
+	identities, err := age.ParseIdentities(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing age identity in %s: %w", path, err)
+	}
+
+	recipients := make([]age.Recipient, 0, len(identities))
+	for _, identity := range identities {
+		if x25519, ok := identity.(*age.X25519Identity); ok {
+			recipients = append(recipients, x25519.Recipient())
+		}
+	}
+
+	return &Key{identities: identities, recipients: recipients}, nil
+}
+
+/*
+IsEncrypted
+Reports whether value is an "enc:<base64-ciphertext>" value rather
+than plaintext.
+*/
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encPrefix)
+}
+
+/*
+Encrypt
+Encrypts plaintext for k's recipients, returning it as an
+"enc:<base64-ciphertext>" string ready to be committed to a
+.cappuccino.yml.
+*/
+func (k *Key) Encrypt(plaintext string) (string, error) {
+	var ciphertext bytes.Buffer
+
+	w, err := age.Encrypt(&ciphertext, k.recipients...)
+	if err != nil {
+		return "", fmt.Errorf("preparing encryption: %w", err)
+	}
+
+	if _, err := io.WriteString(w, plaintext); err != nil {
+		return "", fmt.Errorf("encrypting value: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("encrypting value: %w", err)
+	}
+
+	return encPrefix + base64.StdEncoding.EncodeToString(ciphertext.Bytes()), nil
+}
+
+/*
+Decrypt
+Decrypts an "enc:<base64-ciphertext>" value with k's identities.
+A value that isn't encrypted is returned unchanged, so callers can
+run every config value through Decrypt unconditionally.
+*/
+func (k *Key) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encPrefix))
+	if err != nil {
+		return "", fmt.Errorf("decoding ciphertext: %w", err)
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(raw), k.identities...)
+	if err != nil {
+		return "", fmt.Errorf("decrypting value: %w", err)
+	}
+
+	plaintext, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("decrypting value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,136 @@
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+)
+
+// secretLine matches a "key: !secret plaintext" field, the authoring
+// format for a value that EncryptFile turns into ciphertext.
+var secretLine = regexp.MustCompile(`^(\s*[\w.-]+:\s*)!secret\s+(.*?)\s*$`)
+
+// encLine matches a "key: enc:<base64>" field, the committed format
+// that DecryptFile turns back into a !secret-tagged plaintext.
+var encLine = regexp.MustCompile(`^(\s*[\w.-]+:\s*)"?(enc:[A-Za-z0-9+/=]+)"?\s*$`)
+
+// blockScalarIndicator matches a YAML block (|) or folded (>) scalar
+// header, with its optional chomping (-/+) and explicit indentation
+// digit, e.g. "|", ">-", "|2+". secretLine has no notion of the
+// indented lines that follow such a header, so EncryptFile must
+// refuse these rather than encrypt the literal indicator string.
+var blockScalarIndicator = regexp.MustCompile(`^[|>][+-]?[0-9]*$`)
+
+// suspiciousSecretTag matches a line that looks like it was meant as
+// a "key: !secret ..." field or a "- !secret ..." sequence item but
+// didn't parse as one, so EncryptFile can refuse to leave it in
+// plaintext. It only looks right after a mapping key's colon or a
+// sequence dash (or at the very start of the line), not anywhere in
+// the line -- so prose or a comment that merely mentions "!secret"
+// doesn't trip it.
+var suspiciousSecretTag = regexp.MustCompile(`^\s*(?:[\w.-]+:\s*|-\s*)?!secret\b`)
+
+// suspiciousEncTag is the "enc:" equivalent of suspiciousSecretTag,
+// used by DecryptFile's malformed-line guard.
+var suspiciousEncTag = regexp.MustCompile(`^\s*(?:[\w.-]+:\s*|-\s*)?"?enc:`)
+
+/*
+EncryptFile
+Rewrites path in place, replacing every "!secret plaintext" field
+with its "enc:<base64-ciphertext>" equivalent under k.
+*/
+func EncryptFile(path string, k *Key) error {
+	return transformFile(path, func(line string) (string, error) {
+		match := secretLine.FindStringSubmatch(line)
+		if match == nil {
+			if suspiciousSecretTag.MatchString(line) {
+				return "", fmt.Errorf("found !secret outside of a %q-shaped field, refusing to leave it in plaintext: %q", "key: !secret value", line)
+			}
+			return line, nil
+		}
+
+		if blockScalarIndicator.MatchString(match[2]) {
+			return "", fmt.Errorf("!secret on a block/folded scalar (%q) is not supported, use a plain or quoted scalar instead: %q", match[2], line)
+		}
+
+		ciphertext, err := k.Encrypt(unquote(match[2]))
+		if err != nil {
+			return "", err
+		}
+
+		return match[1] + ciphertext, nil
+	})
+}
+
+/*
+DecryptFile
+Rewrites path in place, replacing every "enc:<base64-ciphertext>"
+field with a "!secret plaintext" field under k, so a template
+author can edit it and re-run EncryptFile.
+*/
+func DecryptFile(path string, k *Key) error {
+	return transformFile(path, func(line string) (string, error) {
+		match := encLine.FindStringSubmatch(line)
+		if match == nil {
+			if suspiciousEncTag.MatchString(line) {
+				return "", fmt.Errorf("found enc: outside of a %q-shaped field, refusing to skip it silently: %q", "key: enc:<ciphertext>", line)
+			}
+			return line, nil
+		}
+
+		plaintext, err := k.Decrypt(match[2])
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s!secret %q", match[1], plaintext), nil
+	})
+}
+
+/*
+transformFile
+Reads path line by line, applies transform to each line and writes
+the result back to path.
+*/
+func transformFile(path string, transform func(string) (string, error)) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for scanner.Scan() {
+		line, err := transform(scanner.Text())
+		if err != nil {
+			return err
+		}
+
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	return ioutil.WriteFile(path, out.Bytes(), 0644)
+}
+
+/*
+unquote
+Strips a single layer of matching quotes from a scalar, as found
+after a !secret tag (`!secret "a value"` or `!secret a-value`).
+*/
+func unquote(value string) string {
+	if len(value) >= 2 {
+		if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,149 @@
+package crypto
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func newTestKey(t *testing.T) *Key {
+	t.Helper()
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("generating identity: %v", err)
+	}
+
+	return &Key{identities: []age.Identity{identity}, recipients: []age.Recipient{identity.Recipient()}}
+}
+
+func TestEncryptFileDecryptFileRoundTrip(t *testing.T) {
+	k := newTestKey(t)
+	path := filepath.Join(t.TempDir(), ".cappuccino.yml")
+	original := "variables:\n  - name: token\n    default: !secret s3kr3t\n"
+	// DecryptFile always re-quotes the plaintext it writes back.
+	wantAfterRoundTrip := "variables:\n  - name: token\n    default: !secret \"s3kr3t\"\n"
+
+	if err := ioutil.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EncryptFile(path, k); err != nil {
+		t.Fatalf("EncryptFile: %v", err)
+	}
+
+	encrypted, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matched := false
+	for _, line := range strings.Split(string(encrypted), "\n") {
+		if encLine.MatchString(line) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		t.Fatalf("expected an enc: field after encrypting, got %s", encrypted)
+	}
+
+	if err := DecryptFile(path, k); err != nil {
+		t.Fatalf("DecryptFile: %v", err)
+	}
+
+	roundTripped, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(roundTripped) != wantAfterRoundTrip {
+		t.Errorf("round trip = %q, want %q", roundTripped, wantAfterRoundTrip)
+	}
+}
+
+func TestEncryptFileRejectsSecretOutsideField(t *testing.T) {
+	k := newTestKey(t)
+	path := filepath.Join(t.TempDir(), ".cappuccino.yml")
+
+	if err := ioutil.WriteFile(path, []byte("- !secret xyz\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EncryptFile(path, k); err == nil {
+		t.Error("expected EncryptFile to refuse a !secret outside a key: field, got nil error")
+	}
+}
+
+func TestEncryptFileRejectsBlockScalarSecret(t *testing.T) {
+	k := newTestKey(t)
+	path := filepath.Join(t.TempDir(), ".cappuccino.yml")
+	original := "variables:\n  - name: token\n    default: !secret |\n      line one\n      line two\n"
+
+	if err := ioutil.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EncryptFile(path, k); err == nil {
+		t.Error("expected EncryptFile to refuse a !secret block scalar, got nil error")
+	}
+}
+
+func TestEncryptFileIgnoresSecretMentionedInProse(t *testing.T) {
+	k := newTestKey(t)
+	path := filepath.Join(t.TempDir(), ".cappuccino.yml")
+	original := "# uses !secret tagged values for secrets\ndescription: \"explains the !secret tag\"\n"
+
+	if err := ioutil.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EncryptFile(path, k); err != nil {
+		t.Fatalf("EncryptFile returned unexpected error for prose mentioning !secret: %v", err)
+	}
+
+	result, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != original {
+		t.Errorf("EncryptFile rewrote a line with no actual secret: got %q, want %q", result, original)
+	}
+}
+
+func TestDecryptFileIgnoresEncMentionedInProse(t *testing.T) {
+	k := newTestKey(t)
+	path := filepath.Join(t.TempDir(), ".cappuccino.yml")
+	original := "description: \"see the enc: prefix convention\"\n"
+
+	if err := ioutil.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DecryptFile(path, k); err != nil {
+		t.Fatalf("DecryptFile returned unexpected error for prose mentioning enc:: %v", err)
+	}
+
+	result, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != original {
+		t.Errorf("DecryptFile rewrote a line with no actual enc: field: got %q, want %q", result, original)
+	}
+}
+
+func TestDecryptFileRejectsEncOutsideField(t *testing.T) {
+	k := newTestKey(t)
+	path := filepath.Join(t.TempDir(), ".cappuccino.yml")
+
+	if err := ioutil.WriteFile(path, []byte("- enc:abcd\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DecryptFile(path, k); err == nil {
+		t.Error("expected DecryptFile to refuse an enc: outside a key: field, got nil error")
+	}
+}
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v2"
+)
+
+/*
+Config
+Structure mirroring the format of a valid .cappuccino.yml file.
+Consists of an engine name, associated version and an array of actions.
+*/
+type Config struct {
+	Engine    string
+	Version   string
+	Sandbox   bool
+	Variables []Variable
+	Actions   []Action
+}
+
+/*
+Variable
+Declares a value a template author wants collected before actions
+run, either interactively or from a values file/--set override.
+Name must match the `x` in a `[cappuccino-var-x]` marker for
+Resolve to thread it through to substitution.
+*/
+type Variable struct {
+	Name     string
+	Prompt   string
+	Default  string
+	Required bool
+	Validate string
+	Secret   bool
+	Confirm  bool
+	Choices  []string
+}
+
+/*
+Action
+Structure mirroring the format of a valid action if a config file.
+Consists of a name and an array of action commands.
+*/
+type Action struct {
+	Name    string
+	Type    string
+	Content []ActionContent
+}
+
+/*
+ActionContent
+Structure mirroring the format of a valid command if a config file.
+Consists of a type, path, command, source, destination, variable,
+path and a value. Env, Timeout, Image and Network only apply to
+"exec" content run with sandboxing enabled.
+*/
+type ActionContent struct {
+	Type        string
+	Command     string
+	Source      string
+	Destination string
+	Variable    string
+	Text        string
+	Path        string
+	Value       string
+	Indent      int
+	Env         map[string]string
+	Timeout     int
+	Image       string
+	Network     string
+}
+
+/*
+LoadConfig
+Reads a .cappuccino.yml file located at path and unmarshals it into
+a Config. Used by every subcommand that needs to inspect or run a
+template, whether it was cloned from git or is sitting on disk.
+*/
+func LoadConfig(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+
+	config := Config{}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return &config, nil
+}
+
+/*
+DisplayVersion
+Displays the current Cappuccino version.
+Please refer to the CHANGELOG for related changes.
+*/
+func DisplayVersion(config *Config) {
+	content := fmt.Sprintf("Detected version: %s", config.Version)
+	Text(content, color.FgYellow)
+}
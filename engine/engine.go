@@ -0,0 +1,324 @@
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CapSens/cappuccino/sandbox"
+	"github.com/CapSens/cappuccino/transaction"
+	"github.com/fatih/color"
+)
+
+/*
+RunMode
+Controls how far Run and the functions it calls actually go.
+ModeExecute performs every action on disk, ModeDryRun only logs
+what would happen, and ModeValidate additionally checks that an
+action is well formed (e.g. that referenced source paths exist)
+without logging a plan or touching the filesystem.
+*/
+type RunMode int
+
+const (
+	ModeExecute RunMode = iota
+	ModeDryRun
+	ModeValidate
+)
+
+/*
+Run
+Takes a Config pointer in argument and loops through the list
+of actions and commands, executing (or simulating, depending on
+mode) one after another, all of it relative to root rather than the
+process's current directory -- so a caller can run several Config
+trees in the same process without chdir-ing between them. values
+resolves every declared or discovered [cappuccino-var-*] marker and
+is threaded down to substitution; pass nil when the caller has no
+variables to apply (e.g. validate). tx, when non-nil, receives an
+inverse op for every mutating action so its caller can roll back on
+error; pass nil to skip journaling (dry-run, validate, or
+--no-rollback). Returns the first error encountered.
+*/
+func Run(config *Config, root string, mode RunMode, values map[string]string, tx *transaction.Transaction) error {
+	if mode == ModeExecute {
+		Text("Starting execution of actions", color.FgYellow)
+		if err := removeGitDirectory(root); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(config.Actions); i++ {
+		if err := processAction(&config.Actions[i], root, mode, values, tx, config.Sandbox); err != nil {
+			return err
+		}
+	}
+
+	if err := ApplyVariableValues(root, values, mode); err != nil {
+		return err
+	}
+
+	if mode == ModeExecute {
+		processWarnings(root)
+	}
+
+	return nil
+}
+
+func processAction(action *Action, root string, mode RunMode, values map[string]string, tx *transaction.Transaction, sandboxed bool) error {
+	if mode != ModeValidate {
+		Text(action.Name, color.FgGreen)
+	}
+
+	for j := 0; j < len(action.Content); j++ {
+		if err := processContent(action, &action.Content[j], root, mode, values, tx, sandboxed); err != nil {
+			return fmt.Errorf("action %q: %w", action.Name, err)
+		}
+	}
+
+	return nil
+}
+
+/*
+logStep
+Logs one planned action line, prefixing it with "[dry-run]" when
+the action is only being simulated.
+*/
+func logStep(content string, mode RunMode) {
+	if mode == ModeDryRun {
+		content = fmt.Sprintf("[dry-run] %s", content)
+	}
+
+	Text(fmt.Sprintf("\t-> %s", content), color.FgGreen)
+}
+
+/*
+processContent
+Takes an ActionContent as a parameter and handles the execution
+of action depending of it's type, resolving every path it touches
+against root. In ModeDryRun it only logs the plan; in ModeValidate
+it only checks the content is well formed. Every mutation performed
+while tx is non-nil is journaled so it can be rolled back.
+*/
+func processContent(action *Action, content *ActionContent, root string, mode RunMode, values map[string]string, tx *transaction.Transaction, sandboxed bool) error {
+	var contentType string
+
+	if content.Type == "" {
+		contentType = action.Type
+	} else {
+		contentType = content.Type
+	}
+
+	switch contentType {
+	case "exec":
+		command := content.Command
+
+		if mode == ModeValidate {
+			if strings.TrimSpace(command) == "" {
+				return fmt.Errorf("exec content has an empty command")
+			}
+			if err := sandbox.ValidateNetwork(content.Network); err != nil {
+				return err
+			}
+			return nil
+		}
+
+		logStep(command, mode)
+		if mode == ModeDryRun {
+			return nil
+		}
+
+		if sandboxed {
+			return executeSandboxed(root, command, content)
+		}
+
+		executableCommand := strings.Split(command, " ")
+		return executeCommand(root, executableCommand[0], executableCommand[1:]...)
+
+	case "replace", "substitute":
+		path := content.Path
+		value := strings.TrimSpace(content.Value)
+		indent := content.Indent
+
+		var variable string
+		if contentType == "replace" {
+			variable = content.Text
+		} else {
+			variable = fmt.Sprintf("[cappuccino-var-%s]", content.Variable)
+			if resolved, ok := values[content.Variable]; ok {
+				value = strings.TrimSpace(resolved)
+			}
+		}
+
+		if mode == ModeValidate {
+			if path != "" {
+				if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+					return fmt.Errorf("substitute content references missing path %q: %w", path, err)
+				}
+			}
+			return nil
+		}
+
+		var shownPath string
+		if content.Path != "" {
+			shownPath = content.Path
+		} else {
+			shownPath = "all files"
+		}
+
+		coloredName := Colored(variable, color.FgCyan)
+		logStep(fmt.Sprintf("%s in %s", coloredName, shownPath), mode)
+		if mode == ModeDryRun {
+			return nil
+		}
+
+		absPath := ""
+		if path != "" {
+			absPath = filepath.Join(root, path)
+		}
+
+		original, readErr := readIfFile(absPath)
+
+		if err := substituteFile(root, &path, &variable, &value, &indent); err != nil {
+			return err
+		}
+
+		if tx != nil && absPath != "" && readErr == nil {
+			tx.Record(fmt.Sprintf("substitute in %s", path), restoreBytes(absPath, original))
+		}
+
+		return nil
+
+	case "copy", "template":
+		var source, destination string
+
+		if contentType == "copy" {
+			source = content.Source
+			destination = content.Destination
+		} else {
+			source = fmt.Sprintf(".cappuccino/%s", content.Path)
+			destination = content.Path
+		}
+
+		absSource := filepath.Join(root, source)
+		absDestination := filepath.Join(root, destination)
+
+		if mode == ModeValidate {
+			if _, err := os.Stat(absSource); err != nil {
+				return fmt.Errorf("%s content references missing source %q: %w", contentType, source, err)
+			}
+			return nil
+		}
+
+		coloredSource := Colored(source, color.FgBlue)
+		coloredDestination := Colored(destination, color.FgBlue)
+		logStep(fmt.Sprintf("%s -> %s", coloredSource, coloredDestination), mode)
+		if mode == ModeDryRun {
+			return nil
+		}
+
+		if err := copyFile(absSource, absDestination); err != nil {
+			return err
+		}
+
+		if tx != nil {
+			tx.Record(fmt.Sprintf("copy %s -> %s", source, destination), func() error {
+				return os.Remove(absDestination)
+			})
+		}
+
+		return nil
+
+	case "move":
+		source := content.Source
+		destination := content.Destination
+		absSource := filepath.Join(root, source)
+		absDestination := filepath.Join(root, destination)
+
+		if mode == ModeValidate {
+			if _, err := os.Stat(absSource); err != nil {
+				return fmt.Errorf("move content references missing source %q: %w", source, err)
+			}
+			return nil
+		}
+
+		coloredSource := Colored(source, color.FgMagenta)
+		coloredDestination := Colored(destination, color.FgMagenta)
+		logStep(fmt.Sprintf("%s -> %s", coloredSource, coloredDestination), mode)
+		if mode == ModeDryRun {
+			return nil
+		}
+
+		if err := moveFile(absSource, absDestination); err != nil {
+			return err
+		}
+
+		if tx != nil {
+			tx.Record(fmt.Sprintf("move %s -> %s", source, destination), func() error {
+				return moveFile(absDestination, absSource)
+			})
+		}
+
+		return nil
+
+	case "delete":
+		path := content.Path
+		absPath := filepath.Join(root, path)
+
+		if mode == ModeValidate {
+			if _, err := os.Stat(absPath); err != nil {
+				return fmt.Errorf("delete content references missing path %q: %w", path, err)
+			}
+			return nil
+		}
+
+		coloredSource := Colored(path, color.FgRed)
+		logStep(coloredSource, mode)
+		if mode == ModeDryRun {
+			return nil
+		}
+
+		original, readErr := readIfFile(absPath)
+
+		if err := deleteFile(absPath); err != nil {
+			return err
+		}
+
+		if tx != nil && readErr == nil {
+			tx.Record(fmt.Sprintf("delete %s", path), restoreBytes(absPath, original))
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+/*
+readIfFile
+Best-effort read of path's current content, used to journal an
+inverse op before a substitute or delete mutates it. A read
+failure (e.g. path is a directory) is reported through err so
+callers simply skip journaling that op, falling back on the
+transaction's snapshot for the guarantee.
+*/
+func readIfFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no path")
+	}
+
+	return ioutil.ReadFile(path)
+}
+
+/*
+restoreBytes
+Returns an inverse op that rewrites path with the bytes it held
+before a substitute or delete action touched it.
+*/
+func restoreBytes(path string, original []byte) func() error {
+	return func() error {
+		return ioutil.WriteFile(path, original, 0644)
+	}
+}
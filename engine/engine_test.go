@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CapSens/cappuccino/transaction"
+)
+
+func writeTestFile(t *testing.T, root, name, content string) {
+	t.Helper()
+
+	if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+}
+
+func readTestFile(t *testing.T, root, name string) string {
+	t.Helper()
+
+	content, err := ioutil.ReadFile(filepath.Join(root, name))
+	if err != nil {
+		t.Fatalf("reading %s: %v", name, err)
+	}
+
+	return string(content)
+}
+
+func TestRunModeValidateCatchesMissingPaths(t *testing.T) {
+	cases := []struct {
+		name    string
+		content ActionContent
+	}{
+		{"copy", ActionContent{Type: "copy", Source: "missing.txt", Destination: "copy.txt"}},
+		{"move", ActionContent{Type: "move", Source: "missing.txt", Destination: "moved.txt"}},
+		{"delete", ActionContent{Type: "delete", Path: "missing.txt"}},
+		{"substitute", ActionContent{Type: "substitute", Path: "missing.txt", Variable: "name", Value: "x"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			root := t.TempDir()
+			config := &Config{Actions: []Action{{Name: c.name, Content: []ActionContent{c.content}}}}
+
+			err := Run(config, root, ModeValidate, nil, nil)
+			if err == nil {
+				t.Fatalf("expected ModeValidate to reject a missing path, got nil error")
+			}
+			if !strings.Contains(err.Error(), "missing.txt") {
+				t.Errorf("error %q does not mention the missing path", err)
+			}
+		})
+	}
+}
+
+func TestRunModeDryRunPerformsNoMutation(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "source.txt", "hello")
+
+	config := &Config{Actions: []Action{
+		{Name: "copy", Content: []ActionContent{{Type: "copy", Source: "source.txt", Destination: "copy.txt"}}},
+	}}
+
+	if err := Run(config, root, ModeDryRun, nil, nil); err != nil {
+		t.Fatalf("Run(ModeDryRun): %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "copy.txt")); !os.IsNotExist(err) {
+		t.Errorf("ModeDryRun created %s on disk", "copy.txt")
+	}
+	if got := readTestFile(t, root, "source.txt"); got != "hello" {
+		t.Errorf("ModeDryRun mutated source.txt: got %q", got)
+	}
+}
+
+func TestRunModeExecuteRecordsRollbackOps(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "source.txt", "hello")
+	writeTestFile(t, root, "moveme.txt", "move-me")
+	writeTestFile(t, root, "sub.txt", "name: [MARKER]")
+	writeTestFile(t, root, "deleteme.txt", "delete-me")
+
+	config := &Config{Actions: []Action{
+		{Name: "copy", Content: []ActionContent{{Type: "copy", Source: "source.txt", Destination: "copy.txt"}}},
+		{Name: "move", Content: []ActionContent{{Type: "move", Source: "moveme.txt", Destination: "moved.txt"}}},
+		{Name: "substitute", Content: []ActionContent{{Type: "replace", Path: "sub.txt", Text: "[MARKER]", Value: "replaced"}}},
+		{Name: "delete", Content: []ActionContent{{Type: "delete", Path: "deleteme.txt"}}},
+	}}
+
+	tx, err := transaction.Begin(root, false)
+	if err != nil {
+		t.Fatalf("transaction.Begin: %v", err)
+	}
+
+	if err := Run(config, root, ModeExecute, nil, tx); err != nil {
+		t.Fatalf("Run(ModeExecute): %v", err)
+	}
+
+	if got := readTestFile(t, root, "copy.txt"); got != "hello" {
+		t.Errorf("copy.txt = %q, want %q", got, "hello")
+	}
+	if got := readTestFile(t, root, "moved.txt"); got != "move-me" {
+		t.Errorf("moved.txt = %q, want %q", got, "move-me")
+	}
+	if got := readTestFile(t, root, "sub.txt"); got != "name: replaced" {
+		t.Errorf("sub.txt = %q, want %q", got, "name: replaced")
+	}
+	if _, err := os.Stat(filepath.Join(root, "deleteme.txt")); !os.IsNotExist(err) {
+		t.Errorf("delete action left deleteme.txt on disk")
+	}
+
+	report, err := tx.Rollback()
+	if err != nil {
+		t.Fatalf("tx.Rollback(): %v", err)
+	}
+
+	wantUndoneInOrder := []string{
+		"delete deleteme.txt",
+		"substitute in sub.txt",
+		"move moveme.txt -> moved.txt",
+		"copy source.txt -> copy.txt",
+	}
+	for i, want := range wantUndoneInOrder {
+		if i >= len(report) || !strings.HasPrefix(report[i], want) {
+			t.Fatalf("report[%d] = %v, want a line starting with %q (full report: %v)", i, safeIndex(report, i), want, report)
+		}
+	}
+
+	if got := readTestFile(t, root, "moveme.txt"); got != "move-me" {
+		t.Errorf("rollback did not restore moveme.txt: got %q", got)
+	}
+	if got := readTestFile(t, root, "sub.txt"); got != "name: [MARKER]" {
+		t.Errorf("rollback did not restore sub.txt: got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(root, "deleteme.txt")); err != nil {
+		t.Errorf("rollback did not restore deleteme.txt: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "copy.txt")); !os.IsNotExist(err) {
+		t.Errorf("rollback left copy.txt on disk")
+	}
+}
+
+func safeIndex(report []string, i int) string {
+	if i >= len(report) {
+		return "<missing>"
+	}
+
+	return report[i]
+}
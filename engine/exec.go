@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"bytes"
+	"os/exec"
+
+	"github.com/fatih/color"
+)
+
+/*
+executeCommand
+Executes a kernel thread safe command with associated arguments
+defined as a vector of infinite sub-components, with dir as its
+working directory. Returns the error reported by the underlying
+command, if any, after printing its stderr.
+*/
+func executeCommand(dir, command string, args ...string) error {
+	cmd := exec.Command(command, args...)
+	cmd.Dir = dir
+
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		Text(stderr.String(), color.FgRed)
+		return err
+	}
+
+	return nil
+}
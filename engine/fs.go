@@ -0,0 +1,199 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+/*
+removeGitDirectory
+Removes the `.git` directory under root after clone, by default.
+*/
+func removeGitDirectory(root string) error {
+	Text("Removing existing .git folder", color.FgGreen)
+
+	coloredSource := Colored("rm -rf .git", color.FgRed)
+	coloredContent := fmt.Sprintf("\t-> %s", coloredSource)
+	Text(coloredContent, color.FgGreen)
+
+	return executeCommand(root, "rm", "-rf", ".git")
+}
+
+/*
+copyFile
+Copies a file from a source to a destination using standard library.
+*/
+func copyFile(source, destination string) (err error) {
+	in, inErr := os.Open(source)
+	out, outErr := os.Create(destination)
+
+	if inErr != nil {
+		return inErr
+	}
+
+	if outErr != nil {
+		return outErr
+	}
+
+	defer in.Close()
+	defer out.Close()
+
+	if _, err = io.Copy(out, in); err != nil {
+		return err
+	}
+
+	return out.Sync()
+}
+
+/*
+deleteFile
+Deletes a standard file using standard library
+*/
+func deleteFile(path string) (err error) {
+	return os.Remove(path)
+}
+
+/*
+moveFile
+Moves a standard file from a source to a destination
+Using both copyFile and deleteFile functions.
+*/
+func moveFile(source, destination string) (err error) {
+	if err = copyFile(source, destination); err != nil {
+		return err
+	}
+
+	if err = deleteFile(source); err != nil {
+		return err
+	}
+
+	return err
+}
+
+/*
+substituteFile
+Dispatches the path information to either substituteInFile
+Or substituteInPath depending of if a path is given or not,
+resolving path against root in the former case.
+*/
+func substituteFile(root string, path, variable, value *string, indent *int) (err error) {
+	if *path != "" {
+		full := filepath.Join(root, *path)
+		return substituteInFile(&full, variable, value, indent)
+	} else {
+		return substituteInPath(root, variable, value, indent)
+	}
+}
+
+/*
+substituteInFile
+Replaces a content in a file using standard library
+*/
+func substituteInFile(path, variable, value *string, indent *int) (err error) {
+	read, err := ioutil.ReadFile(*path)
+	if err != nil {
+		return err
+	}
+
+	indentedBlock := strings.Join(indentBlock(value, indent), "\n")
+	newBytes := strings.Replace(string(read), *variable, indentedBlock, -1)
+
+	return ioutil.WriteFile(*path, []byte(newBytes), 0)
+}
+
+func indentBlock(content *string, indent *int) (newData []string) {
+	return Map(strings.Split(*content, "\n"), func(s string, i int) string {
+		if i != 0 && indent != nil {
+			return strings.Repeat(" ", *indent) + s
+		} else {
+			return s
+		}
+	})
+}
+
+/*
+substituteInPath
+Replaces a content if found in all files under root.
+This is recursive and can take a while for very large directories
+*/
+func substituteInPath(root string, variable, value *string, indent *int) (err error) {
+	err = filepath.Walk(root, func(filePath string, f os.FileInfo, err error) error {
+		if !f.IsDir() {
+			if err = substituteInFile(&filePath, variable, value, indent); err != nil {
+				return err
+			}
+		}
+
+		return err
+	})
+
+	return err
+}
+
+/*
+processWarnings
+*/
+func processWarnings(root string) {
+	Text("Parsing repository for valuable information", color.FgYellow)
+	filepath.Walk(root, func(filePath string, f os.FileInfo, err error) error {
+		if strings.Contains(filePath, ".cappuccino") {
+			return nil
+		}
+
+		if !f.IsDir() {
+			if err := processWarningInFile(&filePath); err != nil {
+
+			}
+		}
+
+		return err
+	})
+}
+
+func processWarningInFile(path *string) (err error) {
+	f, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(f)
+	line := 1
+
+	for scanner.Scan() {
+		if bytes.Contains(scanner.Bytes(), []byte("[cappuccino-warning]")) {
+			textContent := "\t-> Please make sure to setup needed information located %s in %s"
+			content := fmt.Sprintf(textContent,
+				Colored(fmt.Sprintf("L-%03d", line), color.FgYellow),
+				Colored(*path, color.FgYellow))
+
+			Text(content, color.FgYellow)
+		}
+
+		line++
+	}
+
+	return scanner.Err()
+}
+
+/*
+Map
+Returns a new slice containing the results of applying the function f
+to each string in the original slice.
+*/
+func Map(vs []string, f func(string, int) string) []string {
+	vsm := make([]string, len(vs))
+
+	for i, v := range vs {
+		vsm[i] = f(v, i)
+	}
+
+	return vsm
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+)
+
+/*
+prefix
+Displays a prefix to all engine related messages
+*/
+func prefix() string {
+	return fmt.Sprintf("Engine")
+}
+
+/*
+Text
+Displays a message on the screen using a particular color
+*/
+func Text(content string, attribute color.Attribute, returnOperator ...bool) {
+	returnLine := true
+	var printfContent string
+
+	if len(returnOperator) > 0 {
+		returnLine = returnOperator[0]
+	}
+
+	if returnLine {
+		printfContent = "%s %s\n"
+	} else {
+		printfContent = "\r%s %s"
+	}
+
+	fmt.Printf(printfContent, Colored(prefix(), attribute), content)
+}
+
+/*
+Colored
+Displays a message on the screen using a particular color
+*/
+func Colored(text string, attribute color.Attribute) string {
+	return color.New(attribute).SprintFunc()(text)
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CapSens/cappuccino/sandbox"
+	"github.com/fatih/color"
+)
+
+const defaultSandboxImage = "alpine:latest"
+const defaultSandboxTimeout = 5 * time.Minute
+
+/*
+executeSandboxed
+Runs command inside a disposable Docker container instead of on
+the host, mounting root and passing through the env vars, image,
+network and timeout declared on content. Used in place of
+executeCommand when sandboxing is enabled.
+*/
+func executeSandboxed(root, command string, content *ActionContent) error {
+	image := content.Image
+	if image == "" {
+		image = defaultSandboxImage
+	}
+
+	timeout := defaultSandboxTimeout
+	if content.Timeout > 0 {
+		timeout = time.Duration(content.Timeout) * time.Second
+	}
+
+	env := make([]string, 0, len(content.Env))
+	for name, value := range content.Env {
+		env = append(env, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return sandbox.Run(ctx, sandbox.Options{
+		Image:   image,
+		Command: strings.Split(command, " "),
+		WorkDir: root,
+		Env:     env,
+		Network: content.Network,
+		Stdout:  logWriter{},
+		Stderr:  logWriter{},
+	})
+}
+
+/*
+logWriter
+Adapts Text as an io.Writer so sandboxed container output streams
+into the same logger as every other action.
+*/
+type logWriter struct{}
+
+func (logWriter) Write(p []byte) (int, error) {
+	Text(strings.TrimRight(string(p), "\n"), color.FgGreen)
+	return len(p), nil
+}
@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/CapSens/cappuccino/crypto"
+)
+
+/*
+DecryptConfig
+Walks config looking for "enc:<base64-ciphertext>" values (on
+Variable.Default and ActionContent.Value) and replaces them with
+their plaintext, resolving the key from keyFile only if the config
+actually contains an encrypted value, so templates without secrets
+never require one. Every other code path keeps working with plain
+strings, as if they had been authored that way.
+*/
+func DecryptConfig(config *Config, keyFile string) error {
+	if !hasEncryptedValues(config) {
+		return nil
+	}
+
+	key, err := crypto.ResolveKey(keyFile)
+	if err != nil {
+		return err
+	}
+
+	for i := range config.Variables {
+		plaintext, err := key.Decrypt(config.Variables[i].Default)
+		if err != nil {
+			return fmt.Errorf("decrypting variable %q: %w", config.Variables[i].Name, err)
+		}
+		config.Variables[i].Default = plaintext
+	}
+
+	for i := range config.Actions {
+		for j := range config.Actions[i].Content {
+			content := &config.Actions[i].Content[j]
+
+			plaintext, err := key.Decrypt(content.Value)
+			if err != nil {
+				return fmt.Errorf("decrypting action %q content: %w", config.Actions[i].Name, err)
+			}
+			content.Value = plaintext
+		}
+	}
+
+	return nil
+}
+
+func hasEncryptedValues(config *Config) bool {
+	for _, v := range config.Variables {
+		if crypto.IsEncrypted(v.Default) {
+			return true
+		}
+	}
+
+	for _, action := range config.Actions {
+		for _, content := range action.Content {
+			if crypto.IsEncrypted(content.Value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
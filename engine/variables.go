@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+/*
+ApplyVariableValues
+Substitutes every resolved [cappuccino-var-*] marker across every
+file under root, covering markers that no explicit "substitute"
+action referenced. Safe to run after actions have already handled
+some of them: a marker that no longer exists in a file is simply
+left untouched.
+*/
+func ApplyVariableValues(root string, values map[string]string, mode RunMode) error {
+	if mode == ModeValidate {
+		return nil
+	}
+
+	for name, value := range values {
+		variable := fmt.Sprintf("[cappuccino-var-%s]", name)
+		trimmed := strings.TrimSpace(value)
+
+		coloredName := Colored(variable, color.FgCyan)
+		logStep(fmt.Sprintf("%s in %s", coloredName, "all files"), mode)
+
+		if mode == ModeDryRun {
+			continue
+		}
+
+		if err := substituteInPath(root, &variable, &trimmed, nil); err != nil {
+			return fmt.Errorf("substituting %s: %w", variable, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const containerWorkdir = "/workspace"
+
+/*
+Options
+Describes a single disposable container run: what image to use,
+the command to execute inside it, the host directory to mount as
+its working directory, environment variables to pass through, and
+the network mode (mirrors Docker's own "none"/"bridge" values).
+*/
+type Options struct {
+	Image   string
+	Command []string
+	WorkDir string
+	Env     []string
+	Network string
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+/*
+Run
+Pulls Image if it isn't present locally, creates a disposable
+container mounting WorkDir at /workspace, runs Command inside it,
+streams its combined output to Stdout/Stderr, and returns an error
+if the command exits non-zero or ctx is canceled first.
+*/
+func Run(ctx context.Context, opts Options) error {
+	if err := ValidateNetwork(opts.Network); err != nil {
+		return err
+	}
+
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("connecting to docker: %w", err)
+	}
+	defer cli.Close()
+
+	if err := pullImage(ctx, cli, opts.Image); err != nil {
+		return err
+	}
+
+	resp, err := cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:      opts.Image,
+			Cmd:        opts.Command,
+			Env:        opts.Env,
+			WorkingDir: containerWorkdir,
+		},
+		&container.HostConfig{
+			Binds:       []string{fmt.Sprintf("%s:%s", opts.WorkDir, containerWorkdir)},
+			NetworkMode: container.NetworkMode(networkMode(opts.Network)),
+			AutoRemove:  true,
+		},
+		nil, nil, "",
+	)
+	if err != nil {
+		return fmt.Errorf("creating sandbox container: %w", err)
+	}
+
+	if err := cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("starting sandbox container: %w", err)
+	}
+
+	logs, err := cli.ContainerLogs(ctx, resp.ID, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Follow: true})
+	if err == nil {
+		defer logs.Close()
+		stdcopy.StdCopy(opts.Stdout, opts.Stderr, logs)
+	}
+
+	statusCh, errCh := cli.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("waiting for sandbox container: %w", err)
+		}
+	case status := <-statusCh:
+		if status.StatusCode != 0 {
+			return fmt.Errorf("sandboxed command exited with status %d", status.StatusCode)
+		}
+	case <-ctx.Done():
+		if stopErr := stopContainer(resp.ID); stopErr != nil {
+			return fmt.Errorf("sandboxed command timed out and the container could not be stopped: %v (timeout: %w)", stopErr, ctx.Err())
+		}
+		return fmt.Errorf("sandboxed command timed out: %w", ctx.Err())
+	}
+
+	return nil
+}
+
+/*
+stopContainer
+Stops id with a short grace period. ctx's own deadline has already
+passed by the time this is called (it runs from the ctx.Done()
+branch of Run), so it uses a fresh context rather than the expired
+one -- otherwise ContainerStop would fail immediately and the
+container, created with AutoRemove but never stopped, would keep
+running and mutating the bind-mounted working directory after Run
+has already returned a timeout error.
+*/
+func stopContainer(id string) error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("connecting to docker: %w", err)
+	}
+	defer cli.Close()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	timeout := 5
+	return cli.ContainerStop(stopCtx, id, container.StopOptions{Timeout: &timeout})
+}
+
+/*
+networkMode
+Defaults an empty Network to "none", the safest mode for running
+an untrusted template command.
+*/
+func networkMode(mode string) string {
+	if mode == "" {
+		return "none"
+	}
+
+	return mode
+}
+
+/*
+validNetworkModes
+The only network modes a template is allowed to request -- anything
+else (e.g. "host") would defeat the point of sandboxing an untrusted
+exec command.
+*/
+var validNetworkModes = map[string]bool{"": true, "none": true, "bridge": true}
+
+/*
+ValidateNetwork
+Rejects any Network value other than "", "none" or "bridge" before
+a container is ever created.
+*/
+func ValidateNetwork(mode string) error {
+	if !validNetworkModes[mode] {
+		return fmt.Errorf("invalid sandbox network mode %q: must be \"none\" or \"bridge\"", mode)
+	}
+
+	return nil
+}
+
+/*
+pullImage
+Pulls image unless it is already present locally.
+*/
+func pullImage(ctx context.Context, cli *client.Client, image string) error {
+	if _, _, err := cli.ImageInspectWithRaw(ctx, image); err == nil {
+		return nil
+	}
+
+	reader, err := cli.ImagePull(ctx, image, types.ImagePullOptions{})
+	if err != nil {
+		return fmt.Errorf("pulling image %s: %w", image, err)
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(ioutil.Discard, reader)
+	return err
+}
@@ -0,0 +1,107 @@
+package sandbox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/docker/client"
+)
+
+func TestNetworkMode(t *testing.T) {
+	cases := []struct {
+		mode string
+		want string
+	}{
+		{"", "none"},
+		{"bridge", "bridge"},
+	}
+
+	for _, c := range cases {
+		if got := networkMode(c.mode); got != c.want {
+			t.Errorf("networkMode(%q) = %q, want %q", c.mode, got, c.want)
+		}
+	}
+}
+
+func TestValidateNetwork(t *testing.T) {
+	for _, mode := range []string{"", "none", "bridge"} {
+		if err := ValidateNetwork(mode); err != nil {
+			t.Errorf("ValidateNetwork(%q) returned unexpected error: %v", mode, err)
+		}
+	}
+
+	for _, mode := range []string{"host", "container:other", "weird"} {
+		if err := ValidateNetwork(mode); err == nil {
+			t.Errorf("ValidateNetwork(%q) = nil, want an error", mode)
+		}
+	}
+}
+
+func TestPullImageSkipsWhenPresentLocally(t *testing.T) {
+	var pulled bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.41/images/alpine:latest/json":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("{}"))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1.41/images/create":
+			pulled = true
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost(server.URL), client.WithHTTPClient(server.Client()), client.WithVersion("1.41"))
+	if err != nil {
+		t.Fatalf("building docker client: %v", err)
+	}
+	defer cli.Close()
+
+	if err := pullImage(context.Background(), cli, "alpine:latest"); err != nil {
+		t.Fatalf("pullImage returned unexpected error: %v", err)
+	}
+
+	if pulled {
+		t.Error("pullImage pulled an image that was already present locally")
+	}
+}
+
+func TestPullImagePullsWhenMissingLocally(t *testing.T) {
+	var pulled bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.41/images/alpine:latest/json":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"no such image"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1.41/images/create":
+			pulled = true
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("{}\n"))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost(server.URL), client.WithHTTPClient(server.Client()), client.WithVersion("1.41"))
+	if err != nil {
+		t.Fatalf("building docker client: %v", err)
+	}
+	defer cli.Close()
+
+	if err := pullImage(context.Background(), cli, "alpine:latest"); err != nil {
+		t.Fatalf("pullImage returned unexpected error: %v", err)
+	}
+
+	if !pulled {
+		t.Error("pullImage did not pull a missing image")
+	}
+}
@@ -0,0 +1,219 @@
+package transaction
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+/*
+Op
+One inverse operation appended to the journal after an action
+succeeds, so Rollback can report (and, best-effort, replay) what is
+being undone even before the snapshot is restored.
+*/
+type Op struct {
+	Description string
+	Undo        func() error
+}
+
+/*
+Transaction
+Snapshots root to a temporary tarball before the first action runs,
+then collects an inverse Op per successful action. On failure,
+Rollback replays the journal (best effort, for reporting) and
+always restores root from the snapshot, so a partially mutated
+tree never survives an error regardless of how thorough any single
+Undo closure is.
+*/
+type Transaction struct {
+	root         string
+	snapshot     string
+	journal      []Op
+	keepSnapshot bool
+}
+
+/*
+Begin
+Resolves root to an absolute path (os.RemoveAll rejects "."), tars
+it into a temporary file, and returns a Transaction tracking it.
+Call Commit on success or Rollback on failure; both remove the
+snapshot unless keepSnapshot is true.
+*/
+func Begin(root string, keepSnapshot bool) (*Transaction, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", root, err)
+	}
+
+	snapshot, err := ioutil.TempFile("", "cappuccino-snapshot-*.tar.gz")
+	if err != nil {
+		return nil, fmt.Errorf("creating snapshot file: %w", err)
+	}
+	defer snapshot.Close()
+
+	if err := writeTarGz(snapshot, absRoot); err != nil {
+		os.Remove(snapshot.Name())
+		return nil, fmt.Errorf("snapshotting %s: %w", absRoot, err)
+	}
+
+	return &Transaction{root: absRoot, snapshot: snapshot.Name(), keepSnapshot: keepSnapshot}, nil
+}
+
+/*
+Record
+Appends an inverse operation to the journal, to be replayed (in
+reverse order) if the transaction is rolled back.
+*/
+func (t *Transaction) Record(description string, undo func() error) {
+	t.journal = append(t.journal, Op{Description: description, Undo: undo})
+}
+
+/*
+Commit
+Discards the snapshot; the working directory's mutations are kept
+as-is.
+*/
+func (t *Transaction) Commit() error {
+	if t.keepSnapshot {
+		return nil
+	}
+
+	return os.Remove(t.snapshot)
+}
+
+/*
+Rollback
+Replays the journal in reverse order on a best-effort basis, then
+restores root from the snapshot so the working directory ends up
+exactly as it was before the transaction began. Returns a report
+of what was undone, one line per journal entry.
+*/
+func (t *Transaction) Rollback() ([]string, error) {
+	report := make([]string, 0, len(t.journal))
+
+	for i := len(t.journal) - 1; i >= 0; i-- {
+		op := t.journal[i]
+		if err := op.Undo(); err != nil {
+			report = append(report, fmt.Sprintf("%s: failed to undo (%s)", op.Description, err))
+			continue
+		}
+		report = append(report, fmt.Sprintf("%s: undone", op.Description))
+	}
+
+	if err := restoreTarGz(t.snapshot, t.root); err != nil {
+		return report, fmt.Errorf("restoring snapshot: %w", err)
+	}
+
+	if !t.keepSnapshot {
+		os.Remove(t.snapshot)
+	}
+
+	return report, nil
+}
+
+func writeTarGz(w io.Writer, root string) error {
+	gzw := gzip.NewWriter(w)
+	defer gzw.Close()
+
+	tw := tar.NewWriter(gzw)
+	defer tw.Close()
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	})
+}
+
+func restoreTarGz(snapshot, root string) error {
+	file, err := os.Open(snapshot)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+
+	if err := os.RemoveAll(root); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(root, header.Name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			out.Close()
+		}
+	}
+}
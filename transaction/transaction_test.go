@@ -0,0 +1,81 @@
+package transaction
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRollbackRestoresSnapshot(t *testing.T) {
+	root := t.TempDir()
+	filePath := filepath.Join(root, "file.txt")
+	if err := ioutil.WriteFile(filePath, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := Begin(root, false)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	if err := ioutil.WriteFile(filePath, []byte("mutated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tx.Record("mutate file.txt", func() error { return nil })
+
+	if _, err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("reading restored file: %v", err)
+	}
+	if string(content) != "original" {
+		t.Errorf("file.txt = %q, want %q", content, "original")
+	}
+}
+
+func TestBeginResolvesRelativeRoot(t *testing.T) {
+	root := t.TempDir()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	// os.RemoveAll rejects ".", so Begin must resolve it to an absolute
+	// path before Rollback restores it.
+	tx, err := Begin(".", false)
+	if err != nil {
+		t.Fatalf("Begin(\".\"): %v", err)
+	}
+
+	if _, err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback on a relative root: %v", err)
+	}
+}
+
+func TestCommitRemovesSnapshot(t *testing.T) {
+	root := t.TempDir()
+
+	tx, err := Begin(root, false)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	snapshot := tx.snapshot
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	if _, err := os.Stat(snapshot); !os.IsNotExist(err) {
+		t.Errorf("snapshot %s still exists after Commit", snapshot)
+	}
+}
@@ -0,0 +1,229 @@
+package variables
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"gopkg.in/yaml.v2"
+
+	"github.com/CapSens/cappuccino/engine"
+)
+
+var markerRegex = regexp.MustCompile(`\[cappuccino-var-([\w-]+)\]`)
+
+/*
+ResolveOptions
+Controls how variable values are sourced before being threaded
+through to the engine: --set overrides win, then a --values file,
+then an interactive prompt, unless NonInteractive is set, in which
+case a missing required variable fails fast instead of prompting.
+*/
+type ResolveOptions struct {
+	ValuesFile     string
+	Set            map[string]string
+	NonInteractive bool
+}
+
+/*
+Resolve
+Builds the final name -> value map used to substitute every
+[cappuccino-var-*] marker found under root. Variables declared in
+config.Variables are resolved first, in order, then any marker
+discovered by scanning root that wasn't declared gets a generic
+prompt.
+*/
+func Resolve(config *engine.Config, root string, opts ResolveOptions) (map[string]string, error) {
+	fileValues, err := loadValuesFile(opts.ValuesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	discovered, err := scan(root)
+	if err != nil {
+		return nil, err
+	}
+
+	declared := map[string]engine.Variable{}
+	order := make([]string, 0, len(config.Variables)+len(discovered))
+
+	for _, v := range config.Variables {
+		declared[v.Name] = v
+		order = append(order, v.Name)
+	}
+
+	for _, name := range discovered {
+		if _, ok := declared[name]; !ok {
+			declared[name] = engine.Variable{Name: name}
+			order = append(order, name)
+		}
+	}
+
+	values := map[string]string{}
+	for _, name := range order {
+		v := declared[name]
+		value, err := resolveOne(v, fileValues, opts)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+func resolveOne(v engine.Variable, fileValues map[string]string, opts ResolveOptions) (string, error) {
+	if raw, ok := opts.Set[v.Name]; ok {
+		return raw, validate(v, raw)
+	}
+
+	if raw, ok := fileValues[v.Name]; ok {
+		return raw, validate(v, raw)
+	}
+
+	if opts.NonInteractive {
+		if v.Required && v.Default == "" {
+			return "", fmt.Errorf("missing required variable %q (pass --set %s=... or --values)", v.Name, v.Name)
+		}
+		return v.Default, nil
+	}
+
+	answer, err := prompt(v)
+	if err != nil {
+		return "", err
+	}
+
+	return answer, validate(v, answer)
+}
+
+/*
+loadValuesFile
+Reads a flat name -> value YAML mapping, as passed to
+`cappuccino run --values values.yml`. Returns an empty map when no
+file was given.
+*/
+func loadValuesFile(path string) (map[string]string, error) {
+	values := map[string]string{}
+	if path == "" {
+		return values, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading values file %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("parsing values file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+/*
+scan
+Walks root looking for [cappuccino-var-*] markers in every file,
+returning the distinct variable names found, in first-seen order.
+Skips .git, whose pack files are binary, can be arbitrarily large,
+and have nothing to do with the template.
+*/
+func scan(root string) ([]string, error) {
+	seen := map[string]bool{}
+	var names []string
+
+	err := filepath.Walk(root, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if f.IsDir() {
+			if f.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			for _, match := range markerRegex.FindAllStringSubmatch(scanner.Text(), -1) {
+				name := match[1]
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+
+		return scanner.Err()
+	})
+
+	return names, err
+}
+
+/*
+validate
+Checks value against the variable's declared Validate regex, when
+set. A variable without one always passes.
+*/
+func validate(v engine.Variable, value string) error {
+	if v.Validate == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile(v.Validate)
+	if err != nil {
+		return fmt.Errorf("variable %q has an invalid validate pattern: %w", v.Name, err)
+	}
+
+	if !re.MatchString(value) {
+		return fmt.Errorf("value for %q does not match pattern %q", v.Name, v.Validate)
+	}
+
+	return nil
+}
+
+/*
+prompt
+Asks the user for a variable's value interactively, picking the
+survey widget that matches how the variable was declared.
+*/
+func prompt(v engine.Variable) (string, error) {
+	message := v.Prompt
+	if message == "" {
+		message = fmt.Sprintf("Value for %s", v.Name)
+	}
+
+	var answer string
+	var opts []survey.AskOpt
+	if v.Required {
+		opts = append(opts, survey.WithValidator(survey.Required))
+	}
+
+	switch {
+	case v.Confirm:
+		var confirmed bool
+		defaultValue, _ := strconv.ParseBool(v.Default)
+		err := survey.AskOne(&survey.Confirm{Message: message, Default: defaultValue}, &confirmed)
+		return strconv.FormatBool(confirmed), err
+
+	case v.Secret:
+		return answer, survey.AskOne(&survey.Password{Message: message}, &answer, opts...)
+
+	case len(v.Choices) > 0:
+		return answer, survey.AskOne(&survey.Select{Message: message, Options: v.Choices, Default: v.Default}, &answer, opts...)
+
+	default:
+		return answer, survey.AskOne(&survey.Input{Message: message, Default: v.Default}, &answer, opts...)
+	}
+}
@@ -0,0 +1,81 @@
+package variables
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CapSens/cappuccino/engine"
+)
+
+func TestResolveOnePrecedence(t *testing.T) {
+	v := engine.Variable{Name: "token"}
+
+	value, err := resolveOne(v, map[string]string{"token": "from-file"}, ResolveOptions{
+		Set: map[string]string{"token": "from-set"},
+	})
+	if err != nil {
+		t.Fatalf("resolveOne: %v", err)
+	}
+	if value != "from-set" {
+		t.Errorf("--set should win over the values file, got %q", value)
+	}
+
+	value, err = resolveOne(v, map[string]string{"token": "from-file"}, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("resolveOne: %v", err)
+	}
+	if value != "from-file" {
+		t.Errorf("the values file should be used absent --set, got %q", value)
+	}
+}
+
+func TestResolveOneNonInteractive(t *testing.T) {
+	required := engine.Variable{Name: "token", Required: true}
+	if _, err := resolveOne(required, nil, ResolveOptions{NonInteractive: true}); err == nil {
+		t.Error("expected an error for a missing required variable in non-interactive mode")
+	}
+
+	withDefault := engine.Variable{Name: "region", Default: "us-east-1"}
+	value, err := resolveOne(withDefault, nil, ResolveOptions{NonInteractive: true})
+	if err != nil {
+		t.Fatalf("resolveOne: %v", err)
+	}
+	if value != "us-east-1" {
+		t.Errorf("expected the declared default, got %q", value)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	v := engine.Variable{Name: "env", Validate: "^(dev|prod)$"}
+
+	if err := validate(v, "dev"); err != nil {
+		t.Errorf("validate(dev): %v", err)
+	}
+	if err := validate(v, "staging"); err == nil {
+		t.Error("expected validate to reject a value not matching the pattern")
+	}
+}
+
+func TestScanSkipsGitDirectory(t *testing.T) {
+	root := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, ".git", "pack"), []byte("[cappuccino-var-ignored]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "template.txt"), []byte("hello [cappuccino-var-name]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := scan(root)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if len(names) != 1 || names[0] != "name" {
+		t.Errorf("scan = %v, want [name]", names)
+	}
+}
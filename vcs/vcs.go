@@ -0,0 +1,181 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+/*
+Auth
+Carries the credentials needed to reach a remote repository.
+Exactly one of the following combinations is expected to be set:
+an SSH private key (from a file or the running SSH agent), an HTTP
+basic username/password pair, or a token (sent as the password of
+HTTP basic auth).
+*/
+type Auth struct {
+	SSHKeyPath       string
+	SSHKeyPassphrase string
+	UseSSHAgent      bool
+	Username         string
+	Password         string
+	Token            string
+}
+
+/*
+method
+Resolves the Auth struct to a go-git transport.AuthMethod, or nil
+when no credentials were provided (public repositories).
+*/
+func (a *Auth) method(href string) (transport.AuthMethod, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	switch {
+	case a.UseSSHAgent:
+		return gitssh.NewSSHAgentAuth("git")
+
+	case a.SSHKeyPath != "":
+		return gitssh.NewPublicKeysFromFile("git", a.SSHKeyPath, a.SSHKeyPassphrase)
+
+	case a.Token != "":
+		return &githttp.BasicAuth{Username: "x-access-token", Password: a.Token}, nil
+
+	case a.Username != "" || a.Password != "":
+		return &githttp.BasicAuth{Username: a.Username, Password: a.Password}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+/*
+VCSOptions
+Describes what to clone and how. Ref accepts a branch name, a tag
+name, or a commit SHA and is resolved in that order. Depth mirrors
+`git clone --depth`, with 0 meaning a full clone. TargetDir is the
+directory to clone into, defaulting to the repository's name (as
+derived from URL) under the caller's current directory, mirroring
+plain `git clone`.
+*/
+type VCSOptions struct {
+	URL             string
+	Ref             string
+	Depth           int
+	TargetDir       string
+	Auth            Auth
+	InsecureSkipTLS bool
+}
+
+/*
+Repo
+Thin wrapper around a cloned go-git repository, keeping track of
+the on-disk worktree path so callers can keep operating on it with
+the standard library once cloning is done.
+*/
+type Repo struct {
+	*git.Repository
+	path string
+}
+
+/*
+Path
+Returns the filesystem path of the checked out worktree.
+*/
+func (r *Repo) Path() string {
+	return r.path
+}
+
+/*
+Clone
+Clones the repository described by opts into TargetDir (or a
+directory named after the repository, under the current directory,
+when TargetDir is empty) and checks out opts.Ref. Ref is tried as a
+branch, then a tag, then a raw commit SHA, so callers can pass any
+of the three without knowing which one they have.
+*/
+func Clone(ctx context.Context, opts VCSOptions) (*Repo, error) {
+	auth, err := opts.Auth.method(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("resolving auth: %w", err)
+	}
+
+	dir := opts.TargetDir
+	if dir == "" {
+		dir = repoNameFromURL(opts.URL)
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("target directory %s already exists", dir)
+	}
+
+	repo, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+		URL:             opts.URL,
+		Auth:            auth,
+		Depth:           opts.Depth,
+		InsecureSkipTLS: opts.InsecureSkipTLS,
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("cloning %s: %w", opts.URL, err)
+	}
+
+	if opts.Ref != "" {
+		if err := checkoutRef(repo, opts.Ref); err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("checking out %s: %w", opts.Ref, err)
+		}
+	}
+
+	return &Repo{Repository: repo, path: dir}, nil
+}
+
+/*
+repoNameFromURL
+Derives the directory plain `git clone` would create for url: its
+last path segment, minus a trailing ".git".
+*/
+func repoNameFromURL(url string) string {
+	name := strings.TrimSuffix(path.Base(url), ".git")
+	if name == "" || name == "." || name == "/" {
+		name = "cappuccino-checkout"
+	}
+
+	return name
+}
+
+/*
+checkoutRef
+Checks out ref in repo's worktree, trying it in turn as a remote
+branch, a tag, and a raw commit SHA.
+*/
+func checkoutRef(repo *git.Repository, ref string) error {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	if remote, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", ref), true); err == nil {
+		return wt.Checkout(&git.CheckoutOptions{
+			Branch: plumbing.NewBranchReferenceName(ref),
+			Hash:   remote.Hash(),
+			Create: true,
+		})
+	}
+
+	if tag, err := repo.Reference(plumbing.NewTagReferenceName(ref), true); err == nil {
+		return wt.Checkout(&git.CheckoutOptions{Hash: tag.Hash()})
+	}
+
+	return wt.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(ref)})
+}
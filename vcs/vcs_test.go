@@ -0,0 +1,22 @@
+package vcs
+
+import "testing"
+
+func TestRepoNameFromURL(t *testing.T) {
+	cases := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/CapSens/cappuccino.git", "cappuccino"},
+		{"git@github.com:CapSens/cappuccino.git", "cappuccino"},
+		{"https://github.com/CapSens/cappuccino", "cappuccino"},
+		{"https://example.com/", "example.com"},
+		{"", "cappuccino-checkout"},
+	}
+
+	for _, c := range cases {
+		if got := repoNameFromURL(c.url); got != c.want {
+			t.Errorf("repoNameFromURL(%q) = %q, want %q", c.url, got, c.want)
+		}
+	}
+}